@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// applyUpdates rewrites cfg, setting each key in updates to its new value. A line for a key that already
+// exists is rewritten in place, preserving its original whitespace and quote style (or lack of one) -
+// replaceLine's documented shortcoming. Keys with no existing line are appended at the end of the file.
+func applyUpdates(cfg []byte, updates map[string]string) ([]byte, error) {
+	remaining := make(map[string]string, len(updates))
+	for k, v := range updates {
+		remaining[k] = v
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(cfg))
+	var output bytes.Buffer
+	for scanner.Scan() {
+		line := scanner.Text()
+		if key, ok := lineKey(line); ok {
+			if newValue, found := remaining[key]; found {
+				line = setLineValue(line, newValue)
+				delete(remaining, key)
+			}
+		}
+		output.WriteString(line + "\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading input: %w", err)
+	}
+
+	keys := make([]string, 0, len(remaining))
+	for k := range remaining {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&output, "%s=%s\n", k, quoteIfNeeded(remaining[k]))
+	}
+
+	return output.Bytes(), nil
+}
+
+// lineKey returns the variable name a config line assigns to, if it's a "KEY=..." line and not a comment.
+func lineKey(line string) (string, bool) {
+	trimmed := strings.TrimLeft(line, " \t")
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return "", false
+	}
+
+	eqIdx := strings.Index(trimmed, "=")
+	if eqIdx == -1 {
+		return "", false
+	}
+
+	key := strings.TrimRight(trimmed[:eqIdx], " \t")
+	if key == "" {
+		return "", false
+	}
+	for _, r := range key {
+		isLetter := r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z'
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && !isDigit && r != '_' {
+			return "", false
+		}
+	}
+	return key, true
+}
+
+// setLineValue replaces the value portion of a "KEY=..." line with newValue, preserving the line's leading
+// whitespace, whitespace around "=", quote style, and any trailing comment.
+func setLineValue(line, newValue string) string {
+	eqIdx := strings.Index(line, "=")
+	if eqIdx == -1 {
+		return line
+	}
+	prefix := line[:eqIdx+1]
+	rest := line[eqIdx+1:]
+
+	i := 0
+	for i < len(rest) && (rest[i] == ' ' || rest[i] == '\t') {
+		i++
+	}
+	leadingSpace := rest[:i]
+	rest = rest[i:]
+
+	if len(rest) > 0 && (rest[0] == '\'' || rest[0] == '"') {
+		quote := rest[0]
+		if closeIdx := strings.IndexByte(rest[1:], quote); closeIdx != -1 {
+			trailing := rest[1+closeIdx+1:]
+			// Single-quoted values are literal to godotenv - it supports no backslash escapes inside them - so a
+			// newValue containing a single quote can't be represented by escaping; fall back to double-quotes,
+			// which do support a backslash escape, instead.
+			if quote == '\'' && strings.Contains(newValue, "'") {
+				escaped := strings.ReplaceAll(newValue, `"`, `\"`)
+				return prefix + leadingSpace + `"` + escaped + `"` + trailing
+			}
+			escaped := strings.ReplaceAll(newValue, string(quote), `\`+string(quote))
+			return prefix + leadingSpace + string(quote) + escaped + string(quote) + trailing
+		}
+	}
+
+	end := len(rest)
+	for j, c := range rest {
+		if c == ' ' || c == '\t' || c == '#' {
+			end = j
+			break
+		}
+	}
+	trailing := rest[end:]
+
+	return prefix + leadingSpace + quoteIfNeeded(newValue) + trailing
+}
+
+// quoteIfNeeded double-quotes value if it contains characters ("#", whitespace, a quote) that would otherwise
+// change the meaning of an unquoted config line.
+func quoteIfNeeded(value string) string {
+	if !strings.ContainsAny(value, " \t#'\"") {
+		return value
+	}
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
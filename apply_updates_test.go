@@ -0,0 +1,81 @@
+package main
+
+import "testing"
+
+func TestApplyUpdates(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     string
+		updates map[string]string
+		want    string
+	}{
+		{
+			name:    "rewrite existing unquoted value, keep comment",
+			cfg:     "GOOS=windows # target OS\nGOARCH=amd64\n",
+			updates: map[string]string{"GOOS": "linux"},
+			want:    "GOOS=linux # target OS\nGOARCH=amd64\n",
+		},
+		{
+			name:    "rewrite existing quoted value, keep quote style",
+			cfg:     "GOOS='windows' # {update}\n",
+			updates: map[string]string{"GOOS": "linux"},
+			want:    "GOOS='linux' # {update}\n",
+		},
+		{
+			name:    "new value needing quotes replaces unquoted value",
+			cfg:     "NAME=old\n",
+			updates: map[string]string{"NAME": "has space"},
+			want:    `NAME="has space"` + "\n",
+		},
+		{
+			name:    "missing key appended at the end",
+			cfg:     "A=1\n",
+			updates: map[string]string{"B": "2"},
+			want:    "A=1\nB=2\n",
+		},
+		{
+			name:    "new value containing a single quote switches a single-quoted line to double-quotes",
+			cfg:     "NAME='old' # {update}\n",
+			updates: map[string]string{"NAME": "it's new"},
+			want:    `NAME="it's new" # {update}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyUpdates([]byte(tt.cfg), tt.updates)
+			if err != nil {
+				t.Fatalf("applyUpdates() unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("applyUpdates() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildUpdateMap(t *testing.T) {
+	updates, err := buildUpdateMap([]string{"A=1", "B=2"}, nil, `{"C":"3"}`)
+	if err != nil {
+		t.Fatalf("buildUpdateMap() unexpected error: %v", err)
+	}
+	want := map[string]string{"A": "1", "B": "2", "C": "3"}
+	for k, v := range want {
+		if updates[k] != v {
+			t.Errorf("buildUpdateMap()[%q] = %q, want %q", k, updates[k], v)
+		}
+	}
+}
+
+func TestBuildUpdateMapInvalidSet(t *testing.T) {
+	if _, err := buildUpdateMap([]string{"NOEQUALS"}, nil, ""); err == nil {
+		t.Error("buildUpdateMap() expected error for -set without '='")
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	got := diffLines("A=1\nB=2", "A=1\nB=3\nC=4")
+	want := "-B=2\n+B=3\n+C=4\n"
+	if got != want {
+		t.Errorf("diffLines() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+)
+
+// newBackendFlagSet builds a FlagSet carrying the same config-backend flags as the top-level exec mode
+// (--app/--env/--config/--path/--source plus the shared -v/-d/-f globals), for use by subcommands like
+// dump and diff that don't run a child process.
+func newBackendFlagSet(name string) (*flag.FlagSet, *ConfigParams) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	params := &ConfigParams{}
+
+	fs.StringVar(&params.applicationID, "app", "", "AppConfig application identifier")
+	fs.StringVar(&params.environmentID, "env", "", "AppConfig environment identifier")
+	fs.StringVar(&params.configProfileID, "config", "", "AppConfig config profile identifier")
+	fs.StringVar(&params.path, "path", "", "Parameter Store base configuration path")
+	fs.Var(&sources, "source", "a config backend spec \"type:arg\" (one of appconfig, ssm, secretsmanager, file); "+
+		"may be given multiple times, later sources win on key collision")
+
+	fs.BoolVar(&verbose, "v", false, "verbose output")
+	fs.BoolVar(&debug, "d", false, "debug output")
+	fs.BoolVar(&fail, "f", false, "fail if no parameters are found")
+
+	return fs, params
+}
+
+// resolveVars fetches the configured vars for a subcommand's backend flags, preferring --source when given,
+// falling back to --path (SSM) or --app/--env/--config (AppConfig), matching the precedence in readFlags.
+func resolveVars(params *ConfigParams) ([]string, error) {
+	if len(sources) > 0 {
+		return fetchFromSources(sources)
+	}
+
+	var vars []string
+	var err error
+	if params.path != "" {
+		params.path = normalizePath(params.path)
+		vars, err = getConfigFromPS(*params)
+	} else {
+		if params.applicationID == "" || params.environmentID == "" || params.configProfileID == "" {
+			return nil, fmt.Errorf("must specify --source, or --path, or --app/--env/--config")
+		}
+		vars, err = getConfigFromAppConfig(*params)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if fail && len(vars) == 0 {
+		return nil, fmt.Errorf("no parameters found")
+	}
+	return vars, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	colorGreen  = "\033[32m"
+	colorRed    = "\033[31m"
+	colorYellow = "\033[33m"
+	colorReset  = "\033[0m"
+)
+
+// runDiff implements "config-shim diff": it fetches the configured backend's vars and prints how they differ
+// from the current process environment, useful before running with -u.
+func runDiff(args []string) {
+	fs, params := newBackendFlagSet("diff")
+	if err := fs.Parse(args); err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	remote, err := resolveVars(params)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	printDiff(os.Environ(), remote, os.Stdout)
+}
+
+// printDiff writes a colorized +/~ summary of how remoteVars differs from currentVars to w. The comparison is
+// scoped to the keys remoteVars manages: -u only ever rewrites keys already present in the remote config, so
+// unrelated inherited shell variables (PATH, HOME, ...) would otherwise dominate the output as spurious "removed"
+// lines: a key in the environment that the remote config doesn't happen to also define isn't a removal, it was
+// never config-managed to begin with.
+func printDiff(currentVars, remoteVars []string, w io.Writer) {
+	current := toMap(currentVars)
+	remote := toMap(remoteVars)
+
+	keys := make([]string, 0, len(remote))
+	for k := range remote {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		oldVal, hadOld := current[k]
+		newVal := remote[k]
+		switch {
+		case !hadOld:
+			fmt.Fprintf(w, "%s+ %s=%s%s\n", colorGreen, k, newVal, colorReset)
+		case oldVal != newVal:
+			fmt.Fprintf(w, "%s~ %s=%s -> %s%s\n", colorYellow, k, oldVal, newVal, colorReset)
+		}
+	}
+}
+
+func toMap(vars []string) map[string]string {
+	m := make(map[string]string, len(vars))
+	for _, v := range vars {
+		k, val, ok := strings.Cut(v, "=")
+		if !ok {
+			continue
+		}
+		m[k] = val
+	}
+	return m
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPrintDiff(t *testing.T) {
+	current := []string{"KEEP=same", "CHANGE=old", "PATH=/usr/bin", "HOME=/root"}
+	remote := []string{"KEEP=same", "CHANGE=new", "ADD=fresh"}
+
+	var buf bytes.Buffer
+	printDiff(current, remote, &buf)
+	out := buf.String()
+
+	for _, want := range []string{"+ ADD=fresh", "~ CHANGE=old -> new"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("printDiff() output missing %q, got:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"KEEP", "PATH", "HOME"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("printDiff() should not report unchanged or unmanaged keys, got:\n%s", out)
+		}
+	}
+}
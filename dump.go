@@ -0,0 +1,41 @@
+package main
+
+import (
+	"log"
+	"os"
+)
+
+// runDump implements "config-shim dump", which resolves config the same way exec mode does but writes the
+// result to stdout (or -output) in the requested -format instead of running a child process.
+func runDump(args []string) {
+	fs, params := newBackendFlagSet("dump")
+	format := fs.String("format", "env", "output format: env, dotenv, json, shell, systemd")
+	output := fs.String("output", "", "file to write to instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	vars, err := resolveVars(params)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	rendered, err := renderVars(vars, *format)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	w := os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("Error: failed to create %q: %s", *output, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if _, err := w.Write(rendered); err != nil {
+		log.Fatalf("Error: failed to write output: %s", err)
+	}
+}
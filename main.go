@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -25,6 +26,14 @@ var (
 	fail    bool
 	update  bool
 	verbose bool
+
+	watch         bool
+	watchInterval time.Duration
+	reloadSignal  string
+	reloadMode    string
+	killTimeout   time.Duration
+
+	sources sourceSpecs
 )
 
 type ConfigParams struct {
@@ -42,6 +51,24 @@ func main() {
 	// stderr is the default, but clarity is a good thing (especially since the default is not documented)
 	log.SetOutput(os.Stderr)
 
+	// Docker-style subcommands. "exec" is also the implicit default below, for backward compatibility with
+	// invocations that go straight from global flags to the program to run.
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "dump":
+			runDump(os.Args[2:])
+			return
+		case "diff":
+			runDiff(os.Args[2:])
+			return
+		case "update":
+			runUpdate(os.Args[2:])
+			return
+		case "exec":
+			os.Args = append(os.Args[:1], os.Args[2:]...)
+		}
+	}
+
 	params, err := readFlags()
 	if err != nil {
 		log.Fatalf("Error: %s", err)
@@ -56,13 +83,27 @@ func main() {
 	if params.path == "" {
 		getConfigFunction = getConfigFromAppConfig
 	}
+	if len(sources) > 0 {
+		getConfigFunction = func(ConfigParams) ([]string, error) { return fetchFromSources(sources) }
+	}
 
 	vars, err = getConfigFunction(params)
 	if err != nil {
 		log.Fatalf("Error: %s", err)
 	}
+	if fail && len(vars) == 0 {
+		log.Fatal("Error: no parameters found")
+	}
 
 	args := flag.Args()
+
+	if watch {
+		if err = runWatch(params, getConfigFunction, vars, args[0], args[1:]); err != nil {
+			log.Fatalf("Error: %s", err)
+		}
+		return
+	}
+
 	cmd := exec.Command(args[0], args[1:]...)
 	cmd.Env = append(os.Environ(), vars...)
 	cmd.Stdout = os.Stdout
@@ -89,12 +130,26 @@ func readFlags() (ConfigParams, error) {
 
 	flag.StringVar(&params.path, "path", "", "Parameter Store base configuration path")
 
+	flag.Var(&sources, "source", "a config backend spec \"type:arg\" (one of appconfig, ssm, secretsmanager, file); "+
+		"may be given multiple times, later sources win on key collision")
+
 	flag.BoolVar(&update, "u", false, "update config profile with value from environment")
 	flag.BoolVar(&verbose, "v", false, "verbose output")
 	flag.BoolVar(&debug, "d", false, "debug output")
 	flag.BoolVar(&fail, "f", false, "fail if no parameters are found")
+
+	flag.BoolVar(&watch, "watch", false, "supervise the child process and reload config on change, instead of running it once with the config read at startup")
+	flag.DurationVar(&watchInterval, "watch-interval", 30*time.Second, "how often to poll for config changes in watch mode")
+	flag.StringVar(&reloadSignal, "reload-signal", "SIGHUP", "signal to send the child process when config changes and -reload-mode=signal")
+	flag.StringVar(&reloadMode, "reload-mode", "signal", "how to apply a detected config change: \"signal\" sends -reload-signal, \"restart\" terminates and re-execs the child with the new environment")
+	flag.DurationVar(&killTimeout, "kill-timeout", 10*time.Second, "how long to wait for the child to exit gracefully before killing it, in watch mode and on shutdown")
 	flag.Parse()
 
+	if len(sources) > 0 {
+		log.Printf("reading from %d configured --source backend(s)", len(sources))
+		return params, nil
+	}
+
 	if params.path != "" {
 		if !strings.HasSuffix(params.path, "/") {
 			params.path = params.path + "/"
@@ -126,7 +181,9 @@ func readFlags() (ConfigParams, error) {
 }
 
 // getConfigFromAppConfig retrieves all parameters from the AppConfig and returns them as a slice of string, where each
-// string is of the form "param=value"
+// string is of the form "param=value". It's also reused as a single --source fetch, so it does not itself apply
+// -f/--fail: that check only makes sense against the final merged result, and is applied there (see main and
+// fetchFromSources).
 func getConfigFromAppConfig(params ConfigParams) ([]string, error) {
 	configData, err := getLatestConfig(params)
 	if err != nil {
@@ -146,10 +203,6 @@ func getConfigFromAppConfig(params ConfigParams) ([]string, error) {
 		return nil, fmt.Errorf("failed to get vars: %w", err)
 	}
 
-	if fail && len(vars) == 0 {
-		return nil, fmt.Errorf("no parameters found")
-	}
-
 	return vars, nil
 }
 
@@ -182,7 +235,9 @@ func getLatestConfig(params ConfigParams) ([]byte, error) {
 }
 
 // getVars parses a config in env format and returns a slice of variable-value strings like "VAR=value" suitable to
-// supply to the Env attribute of the os/exec Cmd struct.
+// supply to the Env attribute of the os/exec Cmd struct. Values may cross-reference another entry via
+// "${OTHER_VAR}", pull in the host environment via "${env:NAME}", or do an on-demand backend lookup via
+// "${ssm:...}"/"${secretsmanager:...}"; see resolveTemplates.
 func getVars(config []byte) ([]string, error) {
 	vars, err := godotenv.Parse(bytes.NewReader(config))
 	if err != nil {
@@ -196,6 +251,11 @@ func getVars(config []byte) ([]string, error) {
 		log.Printf("vars: %s", vars)
 	}
 
+	vars, err = resolveTemplates(context.Background(), vars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve template references: %w", err)
+	}
+
 	varSlice := make([]string, 0, len(vars))
 	for k, v := range vars {
 		varSlice = append(varSlice, k+"="+v)
@@ -251,7 +311,10 @@ func replaceConfigValues(cfg []byte) ([]byte, error) {
 	return output.Bytes(), nil
 }
 
-// replaceLine handles one line of the config file, replacing the variable value if marked for update
+// replaceLine handles one line of the config file, replacing the variable value if marked for update. newValue
+// may itself reference the host environment or an on-demand backend lookup via "${env:...}"/"${ssm:...}"/
+// "${secretsmanager:...}"; it's only resolved here, for the single value actually being written into the
+// config being updated, not for every variable in the operator's shell.
 func replaceLine(line, variable, newValue string) (string, error) {
 	if !strings.HasPrefix(line, variable) {
 		return line, nil
@@ -266,11 +329,16 @@ func replaceLine(line, variable, newValue string) (string, error) {
 		return line, nil
 	}
 
+	resolvedValue, err := interpolate(context.Background(), newValue)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve template references for %s: %w", variable, err)
+	}
+
 	// this doesn't preserve style (whitespace and quote type or the absence of a quote) but that's fine for now
-	line = fmt.Sprintf("%s='%s' #%s", variable, newValue, parts[1])
+	line = fmt.Sprintf("%s='%s' #%s", variable, resolvedValue, parts[1])
 
 	if debug {
-		log.Printf("updated variable '%s' to '%s' in config file", variable, newValue)
+		log.Printf("updated variable '%s' to '%s' in config file", variable, resolvedValue)
 	}
 	return line, nil
 }
@@ -313,17 +381,15 @@ func deployNewConfig(params ConfigParams, cfg []byte) error {
 }
 
 // getConfigFromPS retrieves all parameters from the given path on Parameter Store and returns them as a slice of
-// string, where each string is of the form "param=value"
+// string, where each string is of the form "param=value". It's also reused as a single --source fetch, so it does
+// not itself apply -f/--fail: that check only makes sense against the final merged result, and is applied there
+// (see main and fetchFromSources).
 func getConfigFromPS(p ConfigParams) ([]string, error) {
 	parameters, err := getAllParameters(p)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get parameters from SSM: %w", err)
 	}
 
-	if fail && len(parameters) == 0 {
-		return nil, fmt.Errorf("no parameters found")
-	}
-
 	return getVarsFromParameters(p.path, parameters), nil
 }
 
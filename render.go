@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// kv is a sorted key/value pair, used by the renderers below to produce deterministic output.
+type kv struct {
+	key   string
+	value string
+}
+
+// renderVars formats a set of "KEY=value" vars per -format, for the dump subcommand.
+func renderVars(vars []string, format string) ([]byte, error) {
+	pairs := sortedPairs(vars)
+
+	switch format {
+	case "env":
+		return renderEnv(pairs), nil
+	case "dotenv":
+		return renderDotenv(pairs), nil
+	case "json":
+		return renderJSON(pairs)
+	case "shell":
+		return renderShell(pairs), nil
+	case "systemd":
+		return renderSystemd(pairs), nil
+	default:
+		return nil, fmt.Errorf("unrecognized -format %q, must be one of env, dotenv, json, shell, systemd", format)
+	}
+}
+
+func sortedPairs(vars []string) []kv {
+	pairs := make([]kv, 0, len(vars))
+	for _, v := range vars {
+		k, val, ok := strings.Cut(v, "=")
+		if !ok {
+			continue
+		}
+		pairs = append(pairs, kv{k, val})
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].key < pairs[j].key })
+	return pairs
+}
+
+// renderEnv emits plain "KEY=value" lines, the same shape getVars/getVarsFromParameters already produce.
+func renderEnv(pairs []kv) []byte {
+	var buf bytes.Buffer
+	for _, p := range pairs {
+		fmt.Fprintf(&buf, "%s=%s\n", p.key, p.value)
+	}
+	return buf.Bytes()
+}
+
+// renderDotenv is like renderEnv but double-quotes values containing whitespace, "#", a quote, or "$" so the file
+// re-parses cleanly with godotenv.Parse: an embedded '"' is backslash-escaped so it doesn't end the quoted value
+// early, and an embedded "$" is backslash-escaped so godotenv treats it as a literal dollar rather than the start
+// of a "${VAR}"/"$VAR" expansion. A bare "'" needs no escaping inside a double-quoted value.
+func renderDotenv(pairs []kv) []byte {
+	var buf bytes.Buffer
+	for _, p := range pairs {
+		value := p.value
+		if strings.ContainsAny(value, " \t#'\"$") {
+			value = strings.ReplaceAll(value, `"`, `\"`)
+			value = strings.ReplaceAll(value, `$`, `\$`)
+			value = `"` + value + `"`
+		}
+		fmt.Fprintf(&buf, "%s=%s\n", p.key, value)
+	}
+	return buf.Bytes()
+}
+
+func renderJSON(pairs []kv) ([]byte, error) {
+	obj := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		obj[p.key] = p.value
+	}
+	out, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// renderShell emits sh/bash "export" statements, single-quoting values with the standard
+// close-quote/escape/reopen-quote trick so embedded single quotes survive.
+func renderShell(pairs []kv) []byte {
+	var buf bytes.Buffer
+	for _, p := range pairs {
+		fmt.Fprintf(&buf, "export %s=%s\n", p.key, shellQuote(p.value))
+	}
+	return buf.Bytes()
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// renderSystemd emits "Environment=" lines suitable for an EnvironmentFile or unit drop-in.
+func renderSystemd(pairs []kv) []byte {
+	var buf bytes.Buffer
+	for _, p := range pairs {
+		fmt.Fprintf(&buf, "Environment=%s=%s\n", p.key, p.value)
+	}
+	return buf.Bytes()
+}
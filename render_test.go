@@ -0,0 +1,64 @@
+package main
+
+import "testing"
+
+func TestRenderVars(t *testing.T) {
+	vars := []string{"B=has space", "A=plain"}
+
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{name: "env", format: "env", want: "A=plain\nB=has space\n"},
+		{name: "dotenv", format: "dotenv", want: "A=plain\nB=\"has space\"\n"},
+		{name: "json", format: "json", want: "{\n  \"A\": \"plain\",\n  \"B\": \"has space\"\n}\n"},
+		{name: "shell", format: "shell", want: "export A='plain'\nexport B='has space'\n"},
+		{name: "systemd", format: "systemd", want: "Environment=A=plain\nEnvironment=B=has space\n"},
+		{name: "unknown format", format: "xml", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderVars(vars, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderVars() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("renderVars() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderDotenvEscaping(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "embedded double quote", value: `a"b`, want: `"a\"b"`},
+		{name: "embedded dollar", value: `pa$$`, want: `"pa\$\$"`},
+		{name: "embedded single quote", value: `it's`, want: `"it's"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(renderDotenv([]kv{{key: "K", value: tt.value}}))
+			want := "K=" + tt.want + "\n"
+			if got != want {
+				t.Errorf("renderDotenv() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	got := shellQuote(`it's a test`)
+	want := `'it'\''s a test'`
+	if got != want {
+		t.Errorf("shellQuote() = %q, want %q", got, want)
+	}
+}
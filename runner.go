@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/appconfigdata"
+)
+
+// signalByName maps the names accepted by -reload-signal to the actual os.Signal. Only the signals that make
+// sense to forward to a child process on Linux are supported.
+var signalByName = map[string]syscall.Signal{
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGUSR1": syscall.SIGUSR1,
+	"SIGUSR2": syscall.SIGUSR2,
+	"SIGTERM": syscall.SIGTERM,
+	"SIGINT":  syscall.SIGINT,
+	"SIGQUIT": syscall.SIGQUIT,
+}
+
+// configHash returns a stable hash of a set of "key=value" vars, independent of the order they were produced in,
+// so it can be used to detect whether the resolved configuration has changed between polls.
+func configHash(vars []string) string {
+	sorted := append([]string(nil), vars...)
+	sort.Strings(sorted)
+
+	h := sha256.New()
+	for _, v := range sorted {
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// runWatch supervises name/args as a long-running child process, polling for config changes every
+// watchInterval (or the interval the backend itself advises, for pollers that offer one) and applying any
+// detected change via -reload-mode. It blocks until the child exits or a fatal error occurs.
+func runWatch(params ConfigParams, getConfigFunction func(ConfigParams) ([]string, error), vars []string, name string, args []string) error {
+	sig, ok := signalByName[strings.ToUpper(reloadSignal)]
+	if !ok {
+		return fmt.Errorf("unrecognized -reload-signal %q", reloadSignal)
+	}
+	if reloadMode != "signal" && reloadMode != "restart" {
+		return fmt.Errorf("unrecognized -reload-mode %q, must be \"signal\" or \"restart\"", reloadMode)
+	}
+
+	ctx := context.Background()
+	poller, err := newConfigPoller(ctx, params, getConfigFunction, vars)
+	if err != nil {
+		return fmt.Errorf("failed to start config poller: %w", err)
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	cmd, err := startChild(name, args, vars)
+	if err != nil {
+		return fmt.Errorf("failed to start %q: %w", name, err)
+	}
+	currentHash := configHash(vars)
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case err := <-exited:
+			if err != nil {
+				return fmt.Errorf("command exited: %w", err)
+			}
+			return nil
+
+		case s := <-sigCh:
+			log.Printf("received %s, forwarding to child and waiting for exit", s)
+			return stopChild(cmd, exited, s.(syscall.Signal), killTimeout)
+
+		case <-ticker.C:
+			newVars, nextInterval, err := poller.poll(ctx)
+			if err != nil {
+				log.Printf("Error: failed to poll config: %s", err)
+				continue
+			}
+			ticker.Reset(nextInterval)
+
+			newHash := configHash(newVars)
+			if newHash == currentHash {
+				continue
+			}
+
+			log.Printf("config change detected, applying via -reload-mode=%s", reloadMode)
+			vars = newVars
+			currentHash = newHash
+
+			if reloadMode == "signal" {
+				if err := cmd.Process.Signal(sig); err != nil {
+					log.Printf("Error: failed to signal child: %s", err)
+				}
+				continue
+			}
+
+			if err := stopChild(cmd, exited, syscall.SIGTERM, killTimeout); err != nil {
+				log.Printf("Error: failed to stop child for restart: %s", err)
+			}
+			cmd, err = startChild(name, args, vars)
+			if err != nil {
+				return fmt.Errorf("failed to restart %q: %w", name, err)
+			}
+			exited = make(chan error, 1)
+			go func() { exited <- cmd.Wait() }()
+		}
+	}
+}
+
+// startChild starts name/args with vars appended to the inherited environment, wiring stdout/stderr straight
+// through like the non-watch code path does.
+func startChild(name string, args, vars []string) (*exec.Cmd, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Env = append(os.Environ(), vars...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if debug {
+		log.Printf("running %q with args: %s and env:\n%s", name, args, strings.Join(cmd.Env, "\n"))
+	} else if verbose {
+		log.Printf("running %q with args: %+v", name, args)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return cmd, nil
+}
+
+// stopChild signals cmd and waits up to timeout for it to exit, escalating to SIGKILL if it doesn't.
+func stopChild(cmd *exec.Cmd, exited chan error, sig syscall.Signal, timeout time.Duration) error {
+	if err := cmd.Process.Signal(sig); err != nil {
+		return err
+	}
+
+	select {
+	case <-exited:
+		return nil
+	case <-time.After(timeout):
+		log.Printf("child did not exit within %s, killing it", timeout)
+		if err := cmd.Process.Kill(); err != nil {
+			return err
+		}
+		<-exited
+		return nil
+	}
+}
+
+// configPoller abstracts how runWatch fetches a fresh set of vars on each tick, so AppConfig's polling
+// contract - reuse the session's configuration token across calls, and honor the interval it advises - can be
+// honored where it applies, while backends with no such contract (SSM, --source) fall back to a plain
+// poll-and-hash comparison on a fixed interval.
+type configPoller interface {
+	// poll fetches the current vars and the interval to wait before the next poll.
+	poll(ctx context.Context) (vars []string, nextInterval time.Duration, err error)
+}
+
+// newConfigPoller picks an AppConfig-aware poller when watch mode is reading from AppConfig by the legacy
+// --app/--env/--config flags (the common case, and the one with a real polling contract to honor), and falls
+// back to a generic poller for SSM and --source backends, which have no session token to reuse.
+func newConfigPoller(ctx context.Context, params ConfigParams, getConfigFunction func(ConfigParams) ([]string, error), vars []string) (configPoller, error) {
+	if len(sources) == 0 && params.path == "" {
+		return newAppConfigPoller(ctx, params, vars)
+	}
+	return &genericPoller{params: params, getConfigFunction: getConfigFunction}, nil
+}
+
+// genericPoller re-fetches the full config every tick at a fixed watchInterval. Used for backends that have no
+// notion of a reusable session token or a server-advised poll interval.
+type genericPoller struct {
+	params            ConfigParams
+	getConfigFunction func(ConfigParams) ([]string, error)
+}
+
+func (p *genericPoller) poll(ctx context.Context) ([]string, time.Duration, error) {
+	vars, err := p.getConfigFunction(p.params)
+	return vars, watchInterval, err
+}
+
+// appConfigPoller polls AWS AppConfig per its intended usage: the configuration token from
+// StartConfigurationSession is reused and advanced across calls via GetLatestConfiguration's
+// NextPollConfigurationToken, and the server's NextPollIntervalInSeconds is honored rather than polling on a
+// fixed local interval. AppConfig returns an empty Configuration when nothing has changed since the last poll,
+// in which case the last known vars are returned unchanged.
+type appConfigPoller struct {
+	client   *appconfigdata.Client
+	token    *string
+	lastVars []string
+}
+
+func newAppConfigPoller(ctx context.Context, params ConfigParams, vars []string) (*appConfigPoller, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client := appconfigdata.NewFromConfig(cfg)
+
+	session, err := client.StartConfigurationSession(ctx, &appconfigdata.StartConfigurationSessionInput{
+		ApplicationIdentifier:          &params.applicationID,
+		ConfigurationProfileIdentifier: &params.configProfileID,
+		EnvironmentIdentifier:          &params.environmentID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &appConfigPoller{client: client, token: session.InitialConfigurationToken, lastVars: vars}, nil
+}
+
+func (p *appConfigPoller) poll(ctx context.Context) ([]string, time.Duration, error) {
+	out, err := p.client.GetLatestConfiguration(ctx, &appconfigdata.GetLatestConfigurationInput{
+		ConfigurationToken: p.token,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	p.token = out.NextPollConfigurationToken
+
+	interval := watchInterval
+	if out.NextPollIntervalInSeconds > 0 {
+		interval = time.Duration(out.NextPollIntervalInSeconds) * time.Second
+	}
+
+	if len(out.Configuration) > 0 {
+		vars, err := getVars(out.Configuration)
+		if err != nil {
+			return nil, interval, err
+		}
+		p.lastVars = vars
+	}
+	return p.lastVars, interval, nil
+}
@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+func TestConfigHash(t *testing.T) {
+	a := []string{"A=1", "B=2"}
+	b := []string{"B=2", "A=1"}
+	c := []string{"A=1", "B=3"}
+
+	if configHash(a) != configHash(b) {
+		t.Errorf("configHash() should be order-independent, got %q and %q", configHash(a), configHash(b))
+	}
+	if configHash(a) == configHash(c) {
+		t.Errorf("configHash() should differ when values differ, both got %q", configHash(a))
+	}
+	if configHash(nil) != configHash([]string{}) {
+		t.Errorf("configHash() should treat nil and empty slices the same")
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// appConfigSource fetches a configuration profile from AWS AppConfig. It wraps the existing
+// getConfigFromAppConfig/updateConfig code path so "--source appconfig:..." behaves the same as the legacy
+// --app/--env/--config flags.
+type appConfigSource struct {
+	params ConfigParams
+}
+
+// newAppConfigSource builds an appConfigSource from an "app/env/config" arg, as used in a "--source
+// appconfig:app/env/config" spec.
+func newAppConfigSource(arg string) (*appConfigSource, error) {
+	parts := strings.Split(arg, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("invalid appconfig source %q, expected \"app/env/config\"", arg)
+	}
+
+	return &appConfigSource{params: ConfigParams{
+		applicationID:   parts[0],
+		environmentID:   parts[1],
+		configProfileID: parts[2],
+	}}, nil
+}
+
+func (s *appConfigSource) Fetch(ctx context.Context) ([]string, error) {
+	return getConfigFromAppConfig(s.params)
+}
+
+func (s *appConfigSource) Update(ctx context.Context, updates map[string]string) error {
+	current, err := getLatestConfig(s.params)
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+	return (&appConfigDeployer{params: s.params, content: current}).Deploy(ctx, updates)
+}
+
+// appConfigDeployer applies a set of updates to a config file's text (preserving quoting and comments via
+// applyUpdates) and uploads the result as a new hosted configuration version, starting a deployment for it -
+// the same two AppConfig calls the legacy -u flag's deployNewConfig already made.
+type appConfigDeployer struct {
+	params  ConfigParams
+	content []byte
+}
+
+func (d *appConfigDeployer) Deploy(ctx context.Context, updates map[string]string) error {
+	newContent, err := applyUpdates(d.content, updates)
+	if err != nil {
+		return err
+	}
+	return deployNewConfig(d.params, newContent)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// fileSource reads a local .env-style file. It's intended for local development and testing, where pulling
+// config from AWS isn't possible or desirable.
+type fileSource struct {
+	path string
+}
+
+func (s *fileSource) Fetch(ctx context.Context) ([]string, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", s.path, err)
+	}
+	return getVars(data)
+}
+
+func (s *fileSource) Update(ctx context.Context, updates map[string]string) error {
+	return fmt.Errorf("file source does not support structured updates yet")
+}
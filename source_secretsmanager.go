@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+// secretsManagerSource fetches config from AWS Secrets Manager. arg is either the name/ARN of a single secret
+// holding a JSON object of string values, or a path prefix ending in "/" naming a set of secrets to list and
+// merge, one variable per secret, named after the part of the secret name after the prefix.
+type secretsManagerSource struct {
+	arg string
+}
+
+func (s *secretsManagerSource) Fetch(ctx context.Context) ([]string, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client := secretsmanager.NewFromConfig(cfg)
+
+	if strings.HasSuffix(s.arg, "/") {
+		return s.fetchByPrefix(ctx, client)
+	}
+	return s.fetchSingle(ctx, client)
+}
+
+// fetchSingle fetches a single secret and treats its SecretString as a JSON object of string values.
+func (s *secretsManagerSource) fetchSingle(ctx context.Context, client *secretsmanager.Client) ([]string, error) {
+	out, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(s.arg)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret %q: %w", s.arg, err)
+	}
+
+	var fields map[string]string
+	if err := json.Unmarshal([]byte(aws.ToString(out.SecretString)), &fields); err != nil {
+		return nil, fmt.Errorf("secret %q is not a JSON object of string values: %w", s.arg, err)
+	}
+
+	vars := make([]string, 0, len(fields))
+	for k, v := range fields {
+		vars = append(vars, k+"="+v)
+	}
+	return vars, nil
+}
+
+// fetchByPrefix lists every secret whose name starts with the prefix and batch-fetches their values, one
+// variable per secret.
+func (s *secretsManagerSource) fetchByPrefix(ctx context.Context, client *secretsmanager.Client) ([]string, error) {
+	var names []string
+	var token *string
+	for {
+		out, err := client.ListSecrets(ctx, &secretsmanager.ListSecretsInput{
+			Filters:   []types.Filter{{Key: types.FilterNameStringTypeName, Values: []string{s.arg}}},
+			NextToken: token,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list secrets under %q: %w", s.arg, err)
+		}
+		for _, entry := range out.SecretList {
+			names = append(names, aws.ToString(entry.Name))
+		}
+		if out.NextToken == nil || len(out.SecretList) == 0 {
+			break
+		}
+		token = out.NextToken
+	}
+
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	var vars []string
+	for _, batch := range chunkStrings(names, batchGetSecretValueMaxIDs) {
+		var token *string
+		for {
+			out, err := client.BatchGetSecretValue(ctx, &secretsmanager.BatchGetSecretValueInput{
+				SecretIdList: batch,
+				NextToken:    token,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to batch get secrets under %q: %w", s.arg, err)
+			}
+			for _, secret := range out.SecretValues {
+				name := strings.TrimPrefix(aws.ToString(secret.Name), s.arg)
+				vars = append(vars, name+"="+aws.ToString(secret.SecretString))
+			}
+			if out.NextToken == nil {
+				break
+			}
+			token = out.NextToken
+		}
+	}
+	return vars, nil
+}
+
+// batchGetSecretValueMaxIDs is the maximum number of entries BatchGetSecretValue accepts in SecretIdList per call.
+const batchGetSecretValueMaxIDs = 20
+
+// chunkStrings splits ss into consecutive slices of at most size entries each.
+func chunkStrings(ss []string, size int) [][]string {
+	var chunks [][]string
+	for len(ss) > 0 {
+		n := size
+		if n > len(ss) {
+			n = len(ss)
+		}
+		chunks = append(chunks, ss[:n])
+		ss = ss[n:]
+	}
+	return chunks
+}
+
+func (s *secretsManagerSource) Update(ctx context.Context, updates map[string]string) error {
+	return fmt.Errorf("secretsmanager source does not support structured updates yet")
+}
+
+// fetchSecretsManagerValue fetches a single secret, optionally pulling one field out of its JSON object, for
+// on-demand "${secretsmanager:name#field}" template references.
+func fetchSecretsManagerValue(ctx context.Context, name, field string) (string, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	client := secretsmanager.NewFromConfig(cfg)
+
+	out, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(name)})
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret %q: %w", name, err)
+	}
+	secretString := aws.ToString(out.SecretString)
+	if field == "" {
+		return secretString, nil
+	}
+
+	var fields map[string]string
+	if err := json.Unmarshal([]byte(secretString), &fields); err != nil {
+		return "", fmt.Errorf("secret %q is not a JSON object of string values: %w", name, err)
+	}
+	value, ok := fields[field]
+	if !ok {
+		return "", fmt.Errorf("secret %q has no field %q", name, field)
+	}
+	return value, nil
+}
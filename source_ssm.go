@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// ssmSource fetches all parameters below a Parameter Store path. It wraps the existing
+// getConfigFromPS/getAllParameters code path so "--source ssm:..." behaves the same as the legacy --path flag.
+type ssmSource struct {
+	path string
+}
+
+func (s *ssmSource) Fetch(ctx context.Context) ([]string, error) {
+	return getConfigFromPS(ConfigParams{path: s.path})
+}
+
+func (s *ssmSource) Update(ctx context.Context, updates map[string]string) error {
+	return (&ssmDeployer{path: s.path}).Deploy(ctx, updates)
+}
+
+// ssmDeployer writes each updated key back to Parameter Store under path, overwriting any existing value.
+// Unlike AppConfig, Parameter Store has no single "config file" to rewrite - each key is its own parameter.
+type ssmDeployer struct {
+	path string
+}
+
+func (d *ssmDeployer) Deploy(ctx context.Context, updates map[string]string) error {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return err
+	}
+	client := ssm.NewFromConfig(cfg)
+
+	for k, v := range updates {
+		name := d.path + k
+		_, err := client.PutParameter(ctx, &ssm.PutParameterInput{
+			Name:      aws.String(name),
+			Value:     aws.String(v),
+			Type:      types.ParameterTypeString,
+			Overwrite: aws.Bool(true),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to put SSM parameter %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// fetchSSMValue fetches a single SSM parameter by name, for on-demand "${ssm:/some/param}" template references.
+func fetchSSMValue(ctx context.Context, name string) (string, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	client := ssm.NewFromConfig(cfg)
+
+	out, err := client.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get SSM parameter %q: %w", name, err)
+	}
+	return aws.ToString(out.Parameter.Value), nil
+}
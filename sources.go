@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Source is a config backend that config-shim can fetch variables from and, for backends that support it,
+// write updates back to.
+type Source interface {
+	Fetch(ctx context.Context) ([]string, error)
+	Update(ctx context.Context, updates map[string]string) error
+}
+
+// Deployer persists a set of key/value updates to a backend's authoritative store. It backs the "update"
+// subcommand; the legacy -u flag does not go through a Deployer, though for AppConfig both end in deployNewConfig.
+type Deployer interface {
+	Deploy(ctx context.Context, updates map[string]string) error
+}
+
+// sourceSpecs collects repeated --source flags in the order they were given. It implements flag.Value so the
+// flag package will call Set once per occurrence instead of overwriting a single string.
+type sourceSpecs []string
+
+func (s *sourceSpecs) String() string {
+	if s == nil {
+		return ""
+	}
+	return strings.Join(*s, ",")
+}
+
+func (s *sourceSpecs) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// newSource builds a Source from a "type:arg" spec, e.g. "ssm:/base/", "secretsmanager:myapp/prod" or
+// "file:./override.env".
+func newSource(spec string) (Source, error) {
+	kind, arg, ok := strings.Cut(spec, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid --source %q, expected \"type:arg\"", spec)
+	}
+
+	switch kind {
+	case "appconfig":
+		return newAppConfigSource(arg)
+	case "ssm":
+		return &ssmSource{path: normalizePath(arg)}, nil
+	case "secretsmanager":
+		return &secretsManagerSource{arg: arg}, nil
+	case "file":
+		return &fileSource{path: arg}, nil
+	default:
+		return nil, fmt.Errorf("unrecognized --source type %q", kind)
+	}
+}
+
+// fetchFromSources builds a Source for each spec and fetches them in order, merging the results so that later
+// sources win on key collision.
+func fetchFromSources(specs []string) ([]string, error) {
+	ctx := context.Background()
+
+	varSlices := make([][]string, len(specs))
+	for i, spec := range specs {
+		src, err := newSource(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		vars, err := src.Fetch(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch from source %q: %w", spec, err)
+		}
+		if verbose || debug {
+			log.Printf("read %d vars from source %q", len(vars), spec)
+		}
+		varSlices[i] = vars
+	}
+
+	vars := mergeVars(varSlices...)
+	if fail && len(vars) == 0 {
+		return nil, fmt.Errorf("no parameters found")
+	}
+	return vars, nil
+}
+
+// mergeVars merges "KEY=value" slices in order, later slices winning on key collision. Key order in the output
+// follows first appearance so the result is deterministic regardless of which source introduced a key.
+func mergeVars(varSlices ...[]string) []string {
+	merged := map[string]string{}
+	var order []string
+
+	for _, vars := range varSlices {
+		for _, v := range vars {
+			k, val, ok := strings.Cut(v, "=")
+			if !ok {
+				continue
+			}
+			if _, seen := merged[k]; !seen {
+				order = append(order, k)
+			}
+			merged[k] = val
+		}
+	}
+
+	out := make([]string, 0, len(order))
+	for _, k := range order {
+		out = append(out, k+"="+merged[k])
+	}
+	return out
+}
+
+// normalizePath mirrors readFlags' handling of --path, ensuring an SSM path always ends in "/".
+func normalizePath(path string) string {
+	if path != "" && !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+	return path
+}
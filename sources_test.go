@@ -0,0 +1,45 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMergeVars(t *testing.T) {
+	tests := []struct {
+		name   string
+		slices [][]string
+		want   []string
+	}{
+		{
+			name:   "single source",
+			slices: [][]string{{"A=1", "B=2"}},
+			want:   []string{"A=1", "B=2"},
+		},
+		{
+			name:   "later source wins",
+			slices: [][]string{{"A=1", "B=2"}, {"B=3"}},
+			want:   []string{"A=1", "B=3"},
+		},
+		{
+			name:   "later source adds new keys",
+			slices: [][]string{{"A=1"}, {"B=2"}},
+			want:   []string{"A=1", "B=2"},
+		},
+		{
+			name:   "no sources",
+			slices: nil,
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeVars(tt.slices...)
+			slices.Sort(got)
+			slices.Sort(tt.want)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("mergeVars() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// templateRefPattern matches a single "${...}" template reference: either a bare cross-reference to another key
+// produced by godotenv.Parse ("${OTHER_VAR}"), or an on-demand backend lookup ("${env:NAME}", "${ssm:...}",
+// "${secretsmanager:...}"; see fetchBackendValue).
+var templateRefPattern = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// resolveTemplates resolves every "${...}" reference in each value of vars. "${OTHER_VAR}" is a cross-reference
+// to another key in vars, resolved via a topological pass over the whole map so it doesn't matter which of two
+// referencing entries godotenv.Parse happened to produce first; "${env:NAME}", "${ssm:...}" and
+// "${secretsmanager:...}" are on-demand lookups against the host environment or an AWS backend. A "$$" is an
+// escape for a literal "$". A cycle among cross-references, direct or transitive, is reported as an error naming
+// the full chain of keys involved, rather than silently resolving to an empty string.
+func resolveTemplates(ctx context.Context, vars map[string]string) (map[string]string, error) {
+	r := &templateResolver{
+		ctx:      ctx,
+		raw:      vars,
+		resolved: make(map[string]string, len(vars)),
+		inFlight: make(map[string]bool, len(vars)),
+	}
+	for k := range vars {
+		if _, err := r.resolve(k, nil); err != nil {
+			return nil, err
+		}
+	}
+	return r.resolved, nil
+}
+
+// templateResolver resolves each key of raw at most once, memoizing into resolved, and detects cycles among keys
+// currently being resolved via inFlight.
+type templateResolver struct {
+	ctx      context.Context
+	raw      map[string]string
+	resolved map[string]string
+	inFlight map[string]bool
+}
+
+// resolve returns the fully-resolved value of key, recursing into any "${OTHER_VAR}" cross-reference it contains.
+// chain is the sequence of keys already being resolved along the current path, reported if key turns out to
+// already be one of them.
+func (r *templateResolver) resolve(key string, chain []string) (string, error) {
+	if v, ok := r.resolved[key]; ok {
+		return v, nil
+	}
+	if r.inFlight[key] {
+		return "", fmt.Errorf("cycle detected in template references: %s", strings.Join(append(chain, key), " -> "))
+	}
+	raw, ok := r.raw[key]
+	if !ok {
+		return "", fmt.Errorf("reference to unknown variable %q", key)
+	}
+
+	r.inFlight[key] = true
+	defer delete(r.inFlight, key)
+
+	value, err := interpolateRefs(raw, func(ref string) (string, error) {
+		if kind, arg, ok := strings.Cut(ref, ":"); ok && isBackendKind(kind) {
+			return fetchBackendValue(r.ctx, kind, arg)
+		}
+		return r.resolve(ref, append(chain, key))
+	})
+	if err != nil {
+		return "", err
+	}
+	r.resolved[key] = value
+	return value, nil
+}
+
+// interpolate resolves only the on-demand backend references ("${env:...}", "${ssm:...}", "${secretsmanager:...}")
+// found in raw, leaving any bare "${OTHER_VAR}" cross-reference untouched. It's used where there's no map of
+// other values to cross-reference against, such as resolving the single value replaceLine is about to write.
+func interpolate(ctx context.Context, raw string) (string, error) {
+	return interpolateRefs(raw, func(ref string) (string, error) {
+		kind, arg, ok := strings.Cut(ref, ":")
+		if !ok || !isBackendKind(kind) {
+			return "${" + ref + "}", nil
+		}
+		return fetchBackendValue(ctx, kind, arg)
+	})
+}
+
+// interpolateRefs replaces every "${...}" reference in raw with whatever resolveRef returns for its contents,
+// after swapping out "$$" for a placeholder so it isn't mistaken for the start of a reference; a literal "$$"
+// becomes a literal "$" in the output.
+func interpolateRefs(raw string, resolveRef func(ref string) (string, error)) (string, error) {
+	if !strings.Contains(raw, "$") {
+		return raw, nil
+	}
+
+	const escapePlaceholder = "\x00DOLLAR\x00"
+	raw = strings.ReplaceAll(raw, "$$", escapePlaceholder)
+
+	var firstErr error
+	out := templateRefPattern.ReplaceAllStringFunc(raw, func(match string) string {
+		if firstErr != nil {
+			return match
+		}
+
+		ref := templateRefPattern.FindStringSubmatch(match)[1]
+		value, err := resolveRef(ref)
+		if err != nil {
+			firstErr = err
+			return match
+		}
+		return value
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+
+	return strings.ReplaceAll(out, escapePlaceholder, "$"), nil
+}
+
+// isBackendKind reports whether kind names one of the on-demand backend lookups, as opposed to a bare
+// cross-reference to another config key.
+func isBackendKind(kind string) bool {
+	switch kind {
+	case "env", "ssm", "secretsmanager":
+		return true
+	default:
+		return false
+	}
+}
+
+// fetchBackendValue resolves a single on-demand reference such as "env:HOME", "ssm:/some/param" or
+// "secretsmanager:name#field", without listing the whole backend the way a --source would.
+func fetchBackendValue(ctx context.Context, kind, arg string) (string, error) {
+	switch kind {
+	case "env":
+		return os.Getenv(arg), nil
+	case "ssm":
+		return fetchSSMValue(ctx, arg)
+	case "secretsmanager":
+		name, field, _ := strings.Cut(arg, "#")
+		return fetchSecretsManagerValue(ctx, name, field)
+	default:
+		return "", fmt.Errorf("unrecognized backend reference kind %q", kind)
+	}
+}
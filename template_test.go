@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestResolveTemplates(t *testing.T) {
+	tests := []struct {
+		name string
+		vars map[string]string
+		want map[string]string
+	}{
+		{
+			name: "no references",
+			vars: map[string]string{"A": "plain"},
+			want: map[string]string{"A": "plain"},
+		},
+		{
+			name: "bare cross-reference",
+			vars: map[string]string{"A": "plain", "B": "prefix-${A}"},
+			want: map[string]string{"A": "plain", "B": "prefix-plain"},
+		},
+		{
+			name: "forward cross-reference resolves regardless of map order",
+			vars: map[string]string{"A": "${B}", "B": "value"},
+			want: map[string]string{"A": "value", "B": "value"},
+		},
+		{
+			name: "transitive cross-reference",
+			vars: map[string]string{"A": "${B}", "B": "${C}", "C": "value"},
+			want: map[string]string{"A": "value", "B": "value", "C": "value"},
+		},
+		{
+			name: "escaped dollar is literal",
+			vars: map[string]string{"A": "$$not-a-ref"},
+			want: map[string]string{"A": "$not-a-ref"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveTemplates(context.Background(), tt.vars)
+			if err != nil {
+				t.Fatalf("resolveTemplates() unexpected error: %v", err)
+			}
+			for k, want := range tt.want {
+				if got[k] != want {
+					t.Errorf("resolveTemplates()[%q] = %q, want %q", k, got[k], want)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveTemplatesEnvReference(t *testing.T) {
+	if err := os.Setenv("CONFIG_SHIM_TEST_VAR", "host-value"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv("CONFIG_SHIM_TEST_VAR")
+
+	got, err := resolveTemplates(context.Background(), map[string]string{"A": "${env:CONFIG_SHIM_TEST_VAR}"})
+	if err != nil {
+		t.Fatalf("resolveTemplates() unexpected error: %v", err)
+	}
+	if got["A"] != "host-value" {
+		t.Errorf("resolveTemplates()[A] = %q, want %q", got["A"], "host-value")
+	}
+}
+
+func TestResolveTemplatesUnknownReference(t *testing.T) {
+	_, err := resolveTemplates(context.Background(), map[string]string{"A": "${NOPE}"})
+	if err == nil {
+		t.Fatal("resolveTemplates() expected error for reference to unknown variable")
+	}
+	if !strings.Contains(err.Error(), "NOPE") {
+		t.Errorf("resolveTemplates() error = %q, want it to name the unknown variable", err)
+	}
+}
+
+func TestResolveTemplatesCycle(t *testing.T) {
+	_, err := resolveTemplates(context.Background(), map[string]string{"A": "${B}", "B": "${A}"})
+	if err == nil {
+		t.Fatal("resolveTemplates() expected error for cyclic reference")
+	}
+	if !strings.Contains(err.Error(), "A") || !strings.Contains(err.Error(), "B") {
+		t.Errorf("resolveTemplates() error = %q, want it to name both keys in the cycle", err)
+	}
+}
+
+func TestInterpolateLeavesBareReferenceUntouched(t *testing.T) {
+	got, err := interpolate(context.Background(), "prefix-${OTHER_VAR}")
+	if err != nil {
+		t.Fatalf("interpolate() unexpected error: %v", err)
+	}
+	if got != "prefix-${OTHER_VAR}" {
+		t.Errorf("interpolate() = %q, want bare reference left untouched", got)
+	}
+}
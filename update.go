@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// multiFlag collects repeated flag occurrences in order, the same pattern sourceSpecs uses for --source.
+type multiFlag []string
+
+func (m *multiFlag) String() string { return strings.Join(*m, ",") }
+
+func (m *multiFlag) Set(value string) error {
+	*m = append(*m, value)
+	return nil
+}
+
+// runUpdate implements "config-shim update", an explicit alternative to the legacy -u flag that applies a set
+// of key/value changes from the CLI or a JSON patch instead of only substituting values already present in the
+// local environment.
+func runUpdate(args []string) {
+	fs, params := newBackendFlagSet("update")
+	var sets multiFlag
+	var setFromEnv multiFlag
+	fs.Var(&sets, "set", "KEY=value to set, or KEY=@file to read the value from a file; may be repeated")
+	fs.Var(&setFromEnv, "set-from-env", "KEY to set from that variable's current value in the local environment; may be repeated")
+	setJSON := fs.String("set-json", "", "a JSON object of key/value updates, or @file to read it from a file")
+	dryRun := fs.Bool("dry-run", false, "print a diff of the change to stderr instead of applying it")
+	fs.StringVar(&params.deploymentStrategyID, "strategy", "", "AppConfig deployment strategy identifier, required when updating an appconfig backend")
+	if err := fs.Parse(args); err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	updates, err := buildUpdateMap(sets, setFromEnv, *setJSON)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+	if len(updates) == 0 {
+		log.Fatal("Error: no updates given; use -set, -set-from-env, or -set-json")
+	}
+
+	if err := applyUpdate(context.Background(), params, updates, *dryRun); err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+}
+
+// buildUpdateMap merges -set, -set-from-env, and -set-json into a single key/value update map.
+func buildUpdateMap(sets, setFromEnv []string, setJSON string) (map[string]string, error) {
+	updates := make(map[string]string)
+
+	for _, s := range sets {
+		key, val, ok := strings.Cut(s, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid -set %q, expected KEY=value", s)
+		}
+		resolved, err := resolveSetValue(val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve -set %s: %w", key, err)
+		}
+		updates[key] = resolved
+	}
+
+	for _, key := range setFromEnv {
+		val, ok := os.LookupEnv(key)
+		if !ok {
+			return nil, fmt.Errorf("-set-from-env %s: not set in the local environment", key)
+		}
+		updates[key] = val
+	}
+
+	if setJSON != "" {
+		data, err := resolveSetValue(setJSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve -set-json: %w", err)
+		}
+		var patch map[string]string
+		if err := json.Unmarshal([]byte(data), &patch); err != nil {
+			return nil, fmt.Errorf("-set-json is not a JSON object of string values: %w", err)
+		}
+		for k, v := range patch {
+			updates[k] = v
+		}
+	}
+
+	return updates, nil
+}
+
+// resolveSetValue returns value verbatim, unless it starts with "@", in which case the rest is a file path to
+// read the value from.
+func resolveSetValue(value string) (string, error) {
+	if !strings.HasPrefix(value, "@") {
+		return value, nil
+	}
+	data, err := os.ReadFile(strings.TrimPrefix(value, "@"))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\n"), nil
+}
+
+// applyUpdate dispatches a structured update to whichever backend is configured, the same precedence
+// resolveVars uses: --source, then --path, then --app/--env/--config.
+func applyUpdate(ctx context.Context, params *ConfigParams, updates map[string]string, dryRun bool) error {
+	switch {
+	case len(sources) == 1:
+		return updateSource(ctx, sources[0], params.deploymentStrategyID, updates, dryRun)
+	case len(sources) > 1:
+		return fmt.Errorf("update mode supports exactly one --source, got %d", len(sources))
+	case params.path != "":
+		return updateSSM(ctx, normalizePath(params.path), updates, dryRun)
+	case params.applicationID != "" && params.environmentID != "" && params.configProfileID != "":
+		return updateAppConfig(ctx, *params, updates, dryRun)
+	default:
+		return fmt.Errorf("must specify --source, or --path, or --app/--env/--config")
+	}
+}
+
+func updateSource(ctx context.Context, spec, strategyID string, updates map[string]string, dryRun bool) error {
+	kind, arg, ok := strings.Cut(spec, ":")
+	if !ok {
+		return fmt.Errorf("invalid --source %q, expected \"type:arg\"", spec)
+	}
+
+	switch kind {
+	case "appconfig":
+		src, err := newAppConfigSource(arg)
+		if err != nil {
+			return err
+		}
+		src.params.deploymentStrategyID = strategyID
+		return updateAppConfig(ctx, src.params, updates, dryRun)
+	case "ssm":
+		return updateSSM(ctx, normalizePath(arg), updates, dryRun)
+	default:
+		return fmt.Errorf("--source type %q does not support structured updates yet", kind)
+	}
+}
+
+func updateAppConfig(ctx context.Context, params ConfigParams, updates map[string]string, dryRun bool) error {
+	if params.deploymentStrategyID == "" {
+		return fmt.Errorf("deployment strategy ID is required to update an appconfig backend. Use --strategy flag")
+	}
+
+	current, err := getLatestConfig(params)
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+
+	if dryRun {
+		newContent, err := applyUpdates(current, updates)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(os.Stderr, diffLines(string(current), string(newContent)))
+		return nil
+	}
+
+	deployer := &appConfigDeployer{params: params, content: current}
+	return deployer.Deploy(ctx, updates)
+}
+
+func updateSSM(ctx context.Context, path string, updates map[string]string, dryRun bool) error {
+	if dryRun {
+		current, err := getAllParameters(ConfigParams{path: path})
+		if err != nil {
+			return fmt.Errorf("failed to get parameters from SSM: %w", err)
+		}
+		oldVars := getVarsFromParameters(path, current)
+		newVars := mergeVars(oldVars, mapToVars(updates))
+		fmt.Fprint(os.Stderr, diffLines(strings.Join(oldVars, "\n"), strings.Join(newVars, "\n")))
+		return nil
+	}
+
+	deployer := &ssmDeployer{path: path}
+	return deployer.Deploy(ctx, updates)
+}
+
+// mapToVars renders a key/value map as "KEY=value" entries, in the same shape getVars produces.
+func mapToVars(m map[string]string) []string {
+	vars := make([]string, 0, len(m))
+	for k, v := range m {
+		vars = append(vars, k+"="+v)
+	}
+	return vars
+}
+
+// diffLines is a minimal line-level diff (add/remove, no hunks or context) between old and new, enough to
+// review a config update before applying it.
+func diffLines(oldText, newText string) string {
+	oldLines := strings.Split(strings.TrimRight(oldText, "\n"), "\n")
+	newLines := strings.Split(strings.TrimRight(newText, "\n"), "\n")
+
+	oldSet := make(map[string]bool, len(oldLines))
+	for _, l := range oldLines {
+		oldSet[l] = true
+	}
+	newSet := make(map[string]bool, len(newLines))
+	for _, l := range newLines {
+		newSet[l] = true
+	}
+
+	var buf strings.Builder
+	for _, l := range oldLines {
+		if !newSet[l] {
+			fmt.Fprintf(&buf, "-%s\n", l)
+		}
+	}
+	for _, l := range newLines {
+		if !oldSet[l] {
+			fmt.Fprintf(&buf, "+%s\n", l)
+		}
+	}
+	return buf.String()
+}